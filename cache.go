@@ -0,0 +1,160 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+const (
+	defaultCacheTTL     = 7 * 24 * time.Hour
+	defaultCacheMaxSize = 100 * 1024 * 1024 // 100MB
+)
+
+// Cache is a content-addressed, on-disk store of LLMResponses keyed by
+// sha256(model || system prompt || file content). Re-running the hook
+// on an unchanged file skips the HTTP call entirely.
+type Cache struct {
+	dir     string
+	ttl     time.Duration
+	maxSize int64
+}
+
+// defaultCacheDir returns ~/.cache/golang-best-practices-check/,
+// falling back to a relative path if the home directory can't be
+// determined.
+func defaultCacheDir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ".cache/golang-best-practices-check"
+	}
+	return filepath.Join(home, ".cache", "golang-best-practices-check")
+}
+
+// newCache builds a Cache rooted at dir, creating it if necessary.
+func newCache(dir string, ttl time.Duration, maxSize int64) (*Cache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("error creating cache directory %s: %v", dir, err)
+	}
+	return &Cache{dir: dir, ttl: ttl, maxSize: maxSize}, nil
+}
+
+// cacheKey derives the content-addressed key for a query: the same
+// model, system prompt and file content always hash to the same key.
+// runLinters is folded in too, not the lint output itself, so toggling
+// policy.RunLinters invalidates stale entries without paying the cost
+// of running the linters just to compute the key.
+func cacheKey(model, system, content string, runLinters bool) string {
+	h := sha256.New()
+	h.Write([]byte(model))
+	h.Write([]byte("\x00"))
+	h.Write([]byte(system))
+	h.Write([]byte("\x00"))
+	h.Write([]byte(content))
+	h.Write([]byte("\x00"))
+	if runLinters {
+		h.Write([]byte{1})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func (c *Cache) path(key string) string {
+	return filepath.Join(c.dir, key+".json")
+}
+
+// Get returns the cached LLMResponse for key, if present and not
+// expired under the cache's TTL.
+func (c *Cache) Get(key string) (*LLMResponse, bool) {
+	path := c.path(key)
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, false
+	}
+	if time.Since(info.ModTime()) > c.ttl {
+		os.Remove(path)
+		return nil, false
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+
+	resp := &LLMResponse{}
+	if err := json.Unmarshal(data, resp); err != nil {
+		return nil, false
+	}
+
+	return resp, true
+}
+
+// Put stores resp under key, then evicts the oldest entries if the
+// cache has grown past its configured max size.
+func (c *Cache) Put(key string, resp *LLMResponse) error {
+	data, err := json.Marshal(resp)
+	if err != nil {
+		return fmt.Errorf("error marshalling cache entry: %v", err)
+	}
+
+	if err := os.WriteFile(c.path(key), data, 0o644); err != nil {
+		return fmt.Errorf("error writing cache entry: %v", err)
+	}
+
+	return c.evictIfOversized()
+}
+
+// evictIfOversized removes the oldest cache entries until the
+// directory's total size is back under maxSize.
+func (c *Cache) evictIfOversized() error {
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return fmt.Errorf("error reading cache directory: %v", err)
+	}
+
+	type cacheFile struct {
+		path    string
+		size    int64
+		modTime time.Time
+	}
+
+	files := make([]cacheFile, 0, len(entries))
+	var total int64
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		files = append(files, cacheFile{
+			path:    filepath.Join(c.dir, entry.Name()),
+			size:    info.Size(),
+			modTime: info.ModTime(),
+		})
+		total += info.Size()
+	}
+
+	if total <= c.maxSize {
+		return nil
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].modTime.Before(files[j].modTime)
+	})
+
+	for _, f := range files {
+		if total <= c.maxSize {
+			break
+		}
+		if err := os.Remove(f.path); err != nil {
+			continue
+		}
+		total -= f.size
+	}
+
+	return nil
+}
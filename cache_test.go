@@ -0,0 +1,116 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCacheKeyDependsOnAllInputs(t *testing.T) {
+	base := cacheKey("model", "system", "content", false)
+
+	cases := map[string]string{
+		"model":   cacheKey("other-model", "system", "content", false),
+		"system":  cacheKey("model", "other-system", "content", false),
+		"content": cacheKey("model", "system", "other-content", false),
+		"lint":    cacheKey("model", "system", "content", true),
+	}
+
+	for name, key := range cases {
+		if key == base {
+			t.Errorf("expected changing %s to change the cache key", name)
+		}
+	}
+
+	if cacheKey("model", "system", "content", false) != base {
+		t.Errorf("expected identical inputs to produce the same key")
+	}
+}
+
+func TestCacheGetPutRoundTrip(t *testing.T) {
+	cache, err := newCache(t.TempDir(), time.Hour, 1<<20)
+	if err != nil {
+		t.Fatalf("newCache: %v", err)
+	}
+
+	key := cacheKey("model", "system", "content", false)
+	if _, ok := cache.Get(key); ok {
+		t.Fatalf("expected a miss before any Put")
+	}
+
+	want := &LLMResponse{FollowsBestPractices: false, Suggestions: "do better"}
+	if err := cache.Put(key, want); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	got, ok := cache.Get(key)
+	if !ok {
+		t.Fatalf("expected a hit after Put")
+	}
+	if got.FollowsBestPractices != want.FollowsBestPractices || got.Suggestions != want.Suggestions {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestCacheGetExpiresEntriesPastTTL(t *testing.T) {
+	cache, err := newCache(t.TempDir(), time.Minute, 1<<20)
+	if err != nil {
+		t.Fatalf("newCache: %v", err)
+	}
+
+	key := cacheKey("model", "system", "content", false)
+	if err := cache.Put(key, &LLMResponse{Suggestions: "stale"}); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	old := time.Now().Add(-2 * time.Minute)
+	if err := os.Chtimes(cache.path(key), old, old); err != nil {
+		t.Fatalf("Chtimes: %v", err)
+	}
+
+	if _, ok := cache.Get(key); ok {
+		t.Fatalf("expected entry older than the TTL to be treated as a miss")
+	}
+	if _, err := os.Stat(cache.path(key)); !os.IsNotExist(err) {
+		t.Fatalf("expected expired entry to be removed from disk, stat err = %v", err)
+	}
+}
+
+func TestCacheEvictsOldestEntriesFirstWhenOversized(t *testing.T) {
+	dir := t.TempDir()
+	cache, err := newCache(dir, time.Hour, 100)
+	if err != nil {
+		t.Fatalf("newCache: %v", err)
+	}
+
+	oldKey := cacheKey("model", "system", "old", false)
+	newKey := cacheKey("model", "system", "new", false)
+
+	if err := cache.Put(oldKey, &LLMResponse{Suggestions: "old entry"}); err != nil {
+		t.Fatalf("Put old: %v", err)
+	}
+	old := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(cache.path(oldKey), old, old); err != nil {
+		t.Fatalf("Chtimes: %v", err)
+	}
+
+	if err := cache.Put(newKey, &LLMResponse{Suggestions: "new entry"}); err != nil {
+		t.Fatalf("Put new: %v", err)
+	}
+
+	if _, err := os.Stat(cache.path(oldKey)); !os.IsNotExist(err) {
+		t.Fatalf("expected the older entry to be evicted first, stat err = %v", err)
+	}
+	if _, err := os.Stat(cache.path(newKey)); err != nil {
+		t.Fatalf("expected the newer entry to survive eviction: %v", err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ReadDir: %v", err)
+	}
+	if len(entries) != 1 || filepath.Base(entries[0].Name()) != filepath.Base(cache.path(newKey)) {
+		t.Fatalf("expected only the newer entry to remain, got %v", entries)
+	}
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// golangciIssue is the subset of `golangci-lint run --out-format=json`
+// we care about.
+type golangciIssue struct {
+	FromLinter string `json:"FromLinter"`
+	Text       string `json:"Text"`
+	Pos        struct {
+		Filename string `json:"Filename"`
+		Line     int    `json:"Line"`
+	} `json:"Pos"`
+}
+
+type golangciOutput struct {
+	Issues []golangciIssue `json:"Issues"`
+}
+
+// staticFindings runs gofmt, go vet and golangci-lint against file and
+// returns their combined findings as short human-readable lines, so the
+// LLM can be told not to repeat what static analysis already caught.
+// Any tool that isn't installed is skipped silently.
+func staticFindings(ctx context.Context, file string) []string {
+	var findings []string
+	findings = append(findings, gofmtFindings(ctx, file)...)
+	findings = append(findings, govetFindings(ctx, file)...)
+	findings = append(findings, golangciFindings(ctx, file)...)
+	return findings
+}
+
+func gofmtFindings(ctx context.Context, file string) []string {
+	out, err := exec.CommandContext(ctx, "gofmt", "-l", file).Output()
+	if err != nil {
+		return nil
+	}
+	if strings.TrimSpace(string(out)) == "" {
+		return nil
+	}
+	return []string{fmt.Sprintf("gofmt: %s is not gofmt-formatted", file)}
+}
+
+// govetFindings vets file's whole package rather than the bare file, so
+// references to symbols defined in sibling files in the same package
+// don't come back as bogus "undefined" diagnostics. The output is then
+// filtered down to lines about this specific file.
+func govetFindings(ctx context.Context, file string) []string {
+	dir := filepath.Dir(file)
+	base := filepath.Base(file)
+
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "go", "vet", ".")
+	cmd.Dir = dir
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err == nil {
+		return nil
+	}
+
+	var findings []string
+	for _, line := range strings.Split(strings.TrimSpace(stderr.String()), "\n") {
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if vetLineFile(line) != base {
+			continue
+		}
+		findings = append(findings, "go vet: "+line)
+	}
+	return findings
+}
+
+// vetLineFile extracts the filename `go vet` prefixes each diagnostic
+// line with (e.g. "./types.go:12:3: message" -> "types.go"), so it can
+// be compared exactly against a candidate file instead of via substring
+// matching, which would wrongly fold "some_types.go" into "types.go".
+func vetLineFile(line string) string {
+	name, _, ok := strings.Cut(line, ":")
+	if !ok {
+		return ""
+	}
+	return filepath.Base(name)
+}
+
+func golangciFindings(ctx context.Context, file string) []string {
+	out, err := exec.CommandContext(ctx, "golangci-lint", "run", "--out-format=json", file).Output()
+	if len(out) == 0 {
+		return nil
+	}
+
+	result := &golangciOutput{}
+	if jsonErr := json.Unmarshal(out, result); jsonErr != nil {
+		return nil
+	}
+	_ = err // golangci-lint exits non-zero when issues are found; the JSON is still valid
+
+	findings := make([]string, 0, len(result.Issues))
+	for _, issue := range result.Issues {
+		findings = append(findings, fmt.Sprintf("%s: %s (line %d)", issue.FromLinter, issue.Text, issue.Pos.Line))
+	}
+	return findings
+}
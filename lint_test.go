@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestGovetFindingsDoesNotFlagCrossFileReferences guards against
+// vetting a bare file in isolation: a file that only compiles as part
+// of its package (because it references a type defined in a sibling
+// file) must not come back as a false "undefined" finding.
+func TestGovetFindingsDoesNotFlagCrossFileReferences(t *testing.T) {
+	dir := t.TempDir()
+	writeFixtureModule(t, dir)
+
+	types := `package fixture
+
+type Shared struct {
+	Name string
+}
+`
+	user := `package fixture
+
+func describe(s Shared) string {
+	return s.Name
+}
+`
+	typesPath := filepath.Join(dir, "types.go")
+	userPath := filepath.Join(dir, "user.go")
+
+	if err := os.WriteFile(typesPath, []byte(types), 0o644); err != nil {
+		t.Fatalf("writing fixture: %v", err)
+	}
+	if err := os.WriteFile(userPath, []byte(user), 0o644); err != nil {
+		t.Fatalf("writing fixture: %v", err)
+	}
+
+	findings := govetFindings(context.Background(), userPath)
+	if len(findings) != 0 {
+		t.Fatalf("expected no false positives vetting a package member, got %v", findings)
+	}
+}
+
+// TestGovetFindingsMatchesFilenameExactly guards against substring
+// matching on the vet output: a package containing both "types.go" and
+// "some_types.go" must not fold the latter's findings into the former
+// just because "types.go" is a suffix of "some_types.go".
+func TestGovetFindingsMatchesFilenameExactly(t *testing.T) {
+	dir := t.TempDir()
+	writeFixtureModule(t, dir)
+
+	clean := `package fixture
+
+func Clean() {}
+`
+	broken := `package fixture
+
+import "fmt"
+
+func Broken() {
+	fmt.Printf("%d\n", "not a number")
+}
+`
+	typesPath := filepath.Join(dir, "types.go")
+	someTypesPath := filepath.Join(dir, "some_types.go")
+
+	if err := os.WriteFile(typesPath, []byte(clean), 0o644); err != nil {
+		t.Fatalf("writing fixture: %v", err)
+	}
+	if err := os.WriteFile(someTypesPath, []byte(broken), 0o644); err != nil {
+		t.Fatalf("writing fixture: %v", err)
+	}
+
+	if findings := govetFindings(context.Background(), typesPath); len(findings) != 0 {
+		t.Fatalf("expected types.go to have no findings of its own, got %v", findings)
+	}
+
+	findings := govetFindings(context.Background(), someTypesPath)
+	if len(findings) == 0 {
+		t.Fatalf("expected some_types.go's Printf mismatch to be reported")
+	}
+	for _, f := range findings {
+		if !strings.Contains(f, "some_types.go") {
+			t.Errorf("expected finding to be attributed to some_types.go, got %q", f)
+		}
+	}
+}
+
+// writeFixtureModule drops a minimal go.mod into dir so `go vet .`
+// resolves it as its own module instead of failing outright because
+// it sits outside any module.
+func writeFixtureModule(t *testing.T, dir string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module fixture\n\ngo 1.21\n"), 0o644); err != nil {
+		t.Fatalf("writing fixture go.mod: %v", err)
+	}
+}
@@ -1,16 +1,14 @@
 package main
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
+	"flag"
 	"fmt"
-	"io"
-	"net/http"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"runtime"
 	"strings"
-	"unicode/utf8"
 )
 
 var ollamaPort = 11435
@@ -21,143 +19,159 @@ type LLMResponse struct {
 }
 
 func main() {
-	ctx, cancel := context.WithCancel(context.Background())
-	files := os.Args[1:] // Files passed as arguments by pre-commit
+	concurrency := flag.Int("concurrency", runtime.NumCPU(), "number of files to analyze in parallel")
+	output := flag.String("output", string(OutputText), "output format: text, json, sarif or checkstyle")
+	flag.Parse()
+	files := flag.Args() // Files passed as arguments by pre-commit
+
+	format := OutputFormat(*output)
+	switch format {
+	case OutputText, OutputJSON, OutputSARIF, OutputCheckstyle:
+	default:
+		fmt.Printf("Unknown --output value %q\n", *output)
+		os.Exit(1)
+	}
+
+	// Machine-readable output owns stdout so it can be piped straight
+	// into a dashboard; human-readable progress moves to stderr.
+	textOut := os.Stdout
+	if format != OutputText {
+		textOut = os.Stderr
+	}
 
 	if len(files) == 0 {
 		fmt.Println("No files provided for the hook.")
 		os.Exit(0)
 	}
 
-	go startOllama(ctx)
+	ctx, cancel := context.WithCancel(context.Background())
+	cfg := loadConfig()
+
+	if cfg.Provider == "ollama" {
+		go startOllama(ctx)
+	}
 	defer cancel()
 
+	client, err := newClient(cfg)
+	if err != nil {
+		fmt.Printf("Error setting up LLM provider: %v\n", err)
+		os.Exit(1)
+	}
+
+	policy, err := loadPolicy(defaultPolicyPath)
+	if err != nil {
+		fmt.Printf("Error loading policy: %v\n", err)
+		os.Exit(1)
+	}
+	system := systemPromptFor(policy)
+
+	cache, err := newCache(cfg.CacheDir, cfg.CacheTTL, cfg.CacheMaxSize)
+	if err != nil {
+		fmt.Printf("Error setting up cache, proceeding uncached: %v\n", err)
+	}
+
 	warn := false
+	fail := false
 
-	if len(files) > 20 {
-		fmt.Println("Skipping as analysing more than 20 files would take too long")
+	if len(files) > policy.MaxFiles {
+		fmt.Printf("Skipping as analysing more than %d files would take too long\n", policy.MaxFiles)
 		os.Exit(0)
 	}
 
+	candidates := make([]string, 0, len(files))
 	for _, file := range files {
 		if !strings.HasSuffix(file, ".go") {
 			continue
 		}
-
-		content, err := os.ReadFile(file)
-		if err != nil {
-			fmt.Printf("Error reading file %s: %v\n", file, err)
+		if !policyAllows(policy, file) {
 			continue
 		}
+		candidates = append(candidates, file)
+	}
+
+	results := analyzeFiles(ctx, client, cache, cfg.Model, system, policy, candidates, *concurrency)
 
-		if utf8.RuneCountInString(string(content)) > 8000 {
-			fmt.Printf("Skipping file %s as it has more than 8000 characters\n", file)
+	for _, result := range results {
+		if result.skipped != "" {
+			fmt.Fprintln(textOut, result.skipped)
 			continue
 		}
 
-		llmResponse, err := queryLLM(file, string(content))
-		if err != nil {
-			fmt.Printf("Error querying LLM for file %s: %v\n", file, err)
+		if result.err != nil {
+			fmt.Fprintln(textOut, result.err)
 			continue
 		}
 
-		if !llmResponse.FollowsBestPractices {
+		if !result.response.FollowsBestPractices {
 			warn = true
-			fmt.Printf("\nFile: %s does not follow best practices:\n", file)
-			fmt.Printf("Suggestions: %s\n", llmResponse.Suggestions)
-			fmt.Printf("--------------------------------------------\n")
+			if policy.FailSeverity() {
+				fail = true
+			}
+			fmt.Fprintf(textOut, "\nFile: %s does not follow best practices:\n", result.file)
+			fmt.Fprintf(textOut, "Suggestions: %s\n", result.response.Suggestions)
+			fmt.Fprintf(textOut, "--------------------------------------------\n")
 		}
 	}
 
 	if warn {
-		fmt.Println("\nWarning: Some files do not follow Golang best practices. Please review the suggestions above.")
+		fmt.Fprintln(textOut, "\nWarning: Some files do not follow Golang best practices. Please review the suggestions above.")
 	} else {
-		fmt.Println("All checked files follow Golang best practices.")
-	}
-	os.Exit(0)
-}
-
-func startOllama(ctx context.Context) {
-	cmd := exec.CommandContext(ctx, "ollama", "serve")
-	cmd.Env = os.Environ()
-	cmd.Env = append(cmd.Env, fmt.Sprintf("OLLAMA_HOST=127.0.0.1:%d", ollamaPort))
-	err := cmd.Run()
-	if err != nil {
-		fmt.Println(err)
+		fmt.Fprintln(textOut, "All checked files follow Golang best practices.")
 	}
-}
-
-type ollamaRequest struct {
-	Model  string `json:"model"`
-	Prompt string `json:"prompt"`
-	Format string `json:"format"`
-	System string `json:"system"`
-	Stream bool   `json:"stream"`
-}
 
-type ollamaResponse struct {
-	Response string `json:"response"`
-}
-
-func queryLLM(filename, content string) (*LLMResponse, error) {
-
-	llmRequest := &ollamaRequest{
-		Model: "qwen2.5-coder:7b",
-		System: fmt.Sprintf(`You check go files given for best practices following the official style guide. You will reply in json format. Only reply with the json output and nothing more. The json response should have this format:
-			A "follows_best_practices" boolean fields and a "suggestions" string field. 
-			Example:
-			{
-				"follows_best_practices": false,
-				"suggestions": "The function name ParseYAMLConfig does not follow the Go best practices as it's repeating the package name bla bla bla..."
-			}
-	    Do NOT include any other field in the json response.
-		Suggestions need to be as short and concise as possible, there can be no suggestions if the file appears to be following the best practices. But always indicate suggestions if the file does not follow the best practices.
-		You are only given files that have been modified in the current commit so you will lack some context, do not criticize the lack of context. Only check for the best practices that you can observe in the file you are checking at the moment.
-		Do not criticize whether the logic makes sense only check for go best practices. You will reply with a json response.
-		`),
-		Prompt: fmt.Sprintf("File to check:\nFilename: %s\nContent:\n%s", filename, content),
-		Format: "json",
-		Stream: false,
+	if format != OutputText {
+		if err := writeFindings(os.Stdout, format, findingsFrom(results, policy)); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing %s output: %v\n", format, err)
+			os.Exit(1)
+		}
 	}
 
-	requestBody, err := json.Marshal(llmRequest)
-	if err != nil {
-		return nil, fmt.Errorf("error marshalling request: %v", err)
+	if fail {
+		os.Exit(1)
 	}
+	os.Exit(0)
+}
 
-	req, err := http.NewRequest("POST", fmt.Sprintf("http://127.0.0.1:%d/api/generate", ollamaPort), bytes.NewBuffer(requestBody))
-	if err != nil {
-		return nil, fmt.Errorf("error creating request: %v", err)
+// policyAllows reports whether file should be analyzed given the
+// policy's include/exclude globs. Exclude takes precedence; an empty
+// include list means everything not excluded is allowed.
+func policyAllows(p *Policy, file string) bool {
+	for _, pattern := range p.Exclude {
+		if ok, _ := filepath.Match(pattern, file); ok {
+			return false
+		}
 	}
-	req.Header.Add("Content-Type", "application/json")
 
-	client := &http.Client{}
-	res, err := client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("error running ollama command")
+	if len(p.Include) == 0 {
+		return true
 	}
 
-	body, err := io.ReadAll(res.Body)
-	if err != nil {
-		return nil, fmt.Errorf("error reading response body %v", err)
+	for _, pattern := range p.Include {
+		if ok, _ := filepath.Match(pattern, file); ok {
+			return true
+		}
 	}
+	return false
+}
 
-	//fmt.Println(string(body))
-
-	oResp := &ollamaResponse{}
-	err = json.Unmarshal(body, oResp)
+func startOllama(ctx context.Context) {
+	cmd := exec.CommandContext(ctx, "ollama", "serve")
+	cmd.Env = os.Environ()
+	cmd.Env = append(cmd.Env, fmt.Sprintf("OLLAMA_HOST=127.0.0.1:%d", ollamaPort))
+	err := cmd.Run()
 	if err != nil {
-		return nil, fmt.Errorf("error unmarshalling response: %v", err)
+		fmt.Println(err)
 	}
+}
 
-	//fmt.Println(oResp.Response)
-
-	llmResponse := &LLMResponse{}
-
-	err = json.Unmarshal([]byte(oResp.Response), llmResponse)
-	if err != nil {
-		return nil, fmt.Errorf("error unmarshalling LLM response: %v", err)
+// queryLLM asks the configured provider to review a single file and
+// returns its parsed LLMResponse. lintFindings, if non-empty, is passed
+// along as issues already caught by static analysis so the model
+// doesn't repeat them.
+func queryLLM(ctx context.Context, client ChatCompletionClient, system, filename, content string, lintFindings []string) (*LLMResponse, error) {
+	prompt := fmt.Sprintf("File to check:\nFilename: %s\nContent:\n%s", filename, content)
+	if len(lintFindings) > 0 {
+		prompt += "\n\nAlready-detected issues (do not repeat these):\n- " + strings.Join(lintFindings, "\n- ")
 	}
-
-	return llmResponse, nil
+	return client.Complete(ctx, system, prompt)
 }
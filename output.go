@@ -0,0 +1,218 @@
+package main
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+)
+
+// OutputFormat selects how findings are rendered.
+type OutputFormat string
+
+const (
+	OutputText       OutputFormat = "text"
+	OutputJSON       OutputFormat = "json"
+	OutputSARIF      OutputFormat = "sarif"
+	OutputCheckstyle OutputFormat = "checkstyle"
+)
+
+// ruleID is used for every finding today since the LLM reports a single
+// pass/fail verdict per file rather than attributing its suggestions to
+// one specific policy rule.
+const ruleID = "best-practices"
+
+// Finding is a single piece of feedback about a file, lifted from an
+// LLMResponse into the shape every output format can render.
+type Finding struct {
+	File     string
+	RuleID   string
+	Severity Severity
+	Message  string
+}
+
+// findingsFrom turns the worker pool's results into Findings, skipping
+// files that followed best practices or failed to analyze.
+func findingsFrom(results []analysisResult, policy *Policy) []Finding {
+	severity := SeverityWarn
+	if policy.FailSeverity() {
+		severity = SeverityFail
+	}
+
+	findings := make([]Finding, 0)
+	for _, result := range results {
+		if result.response == nil || result.response.FollowsBestPractices {
+			continue
+		}
+		findings = append(findings, Finding{
+			File:     result.file,
+			RuleID:   ruleID,
+			Severity: severity,
+			Message:  result.response.Suggestions,
+		})
+	}
+	return findings
+}
+
+// writeFindings renders findings in the requested format to w.
+func writeFindings(w io.Writer, format OutputFormat, findings []Finding) error {
+	switch format {
+	case OutputJSON:
+		return writeJSONFindings(w, findings)
+	case OutputSARIF:
+		return writeSARIF(w, findings)
+	case OutputCheckstyle:
+		return writeCheckstyle(w, findings)
+	default:
+		return fmt.Errorf("unknown output format %q", format)
+	}
+}
+
+func writeJSONFindings(w io.Writer, findings []Finding) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(findings)
+}
+
+// SARIF 2.1.0 types, minimal subset needed for GitHub Code Scanning.
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name  string      `json:"name"`
+	Rules []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID string `json:"id"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+func sarifLevel(s Severity) string {
+	if s == SeverityFail {
+		return "error"
+	}
+	return "warning"
+}
+
+func writeSARIF(w io.Writer, findings []Finding) error {
+	run := sarifRun{
+		Tool: sarifTool{
+			Driver: sarifDriver{
+				Name:  "golang-best-practices-check",
+				Rules: []sarifRule{{ID: ruleID}},
+			},
+		},
+		Results: make([]sarifResult, 0, len(findings)),
+	}
+
+	for _, f := range findings {
+		run.Results = append(run.Results, sarifResult{
+			RuleID:  f.RuleID,
+			Level:   sarifLevel(f.Severity),
+			Message: sarifMessage{Text: f.Message},
+			Locations: []sarifLocation{{
+				PhysicalLocation: sarifPhysicalLocation{
+					ArtifactLocation: sarifArtifactLocation{URI: f.File},
+				},
+			}},
+		})
+	}
+
+	log := sarifLog{
+		Schema:  "https://raw.githubusercontent.com/oasis-tcs/sarif-spec/master/Schemata/sarif-schema-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{run},
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(log)
+}
+
+// Checkstyle XML, consumed by GitLab/Reviewdog.
+type checkstyleRoot struct {
+	XMLName xml.Name         `xml:"checkstyle"`
+	Version string           `xml:"version,attr"`
+	Files   []checkstyleFile `xml:"file"`
+}
+
+type checkstyleFile struct {
+	Name   string           `xml:"name,attr"`
+	Errors []checkstyleItem `xml:"error"`
+}
+
+type checkstyleItem struct {
+	Severity string `xml:"severity,attr"`
+	Message  string `xml:"message,attr"`
+	Source   string `xml:"source,attr"`
+}
+
+func checkstyleSeverity(s Severity) string {
+	if s == SeverityFail {
+		return "error"
+	}
+	return "warning"
+}
+
+func writeCheckstyle(w io.Writer, findings []Finding) error {
+	order := make([]string, 0)
+	byFile := make(map[string][]checkstyleItem)
+
+	for _, f := range findings {
+		if _, ok := byFile[f.File]; !ok {
+			order = append(order, f.File)
+		}
+		byFile[f.File] = append(byFile[f.File], checkstyleItem{
+			Severity: checkstyleSeverity(f.Severity),
+			Message:  f.Message,
+			Source:   f.RuleID,
+		})
+	}
+
+	root := &checkstyleRoot{Version: "4.3"}
+	for _, file := range order {
+		root.Files = append(root.Files, checkstyleFile{Name: file, Errors: byFile[file]})
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	return enc.Encode(root)
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+func TestFindingsFromSkipsPassingAndFailedResults(t *testing.T) {
+	policy := defaultPolicy()
+	results := []analysisResult{
+		{file: "ok.go", response: &LLMResponse{FollowsBestPractices: true}},
+		{file: "bad.go", response: &LLMResponse{FollowsBestPractices: false, Suggestions: "rename X"}},
+		{file: "errored.go", err: errFixture},
+		{file: "skipped.go", skipped: "vendored"},
+	}
+
+	findings := findingsFrom(results, policy)
+	if len(findings) != 1 {
+		t.Fatalf("expected 1 finding, got %d: %+v", len(findings), findings)
+	}
+	if findings[0].File != "bad.go" || findings[0].Message != "rename X" {
+		t.Fatalf("unexpected finding: %+v", findings[0])
+	}
+	if findings[0].Severity != SeverityWarn {
+		t.Fatalf("expected warn severity by default, got %v", findings[0].Severity)
+	}
+}
+
+func TestFindingsFromUsesFailSeverityFromPolicy(t *testing.T) {
+	policy := defaultPolicy()
+	policy.Rules[0].Severity = SeverityFail
+
+	results := []analysisResult{
+		{file: "bad.go", response: &LLMResponse{FollowsBestPractices: false, Suggestions: "x"}},
+	}
+
+	findings := findingsFrom(results, policy)
+	if len(findings) != 1 || findings[0].Severity != SeverityFail {
+		t.Fatalf("expected a fail-severity finding, got %+v", findings)
+	}
+}
+
+func TestWriteJSONFindings(t *testing.T) {
+	var buf bytes.Buffer
+	findings := []Finding{{File: "a.go", RuleID: ruleID, Severity: SeverityWarn, Message: "msg"}}
+	if err := writeJSONFindings(&buf, findings); err != nil {
+		t.Fatalf("writeJSONFindings: %v", err)
+	}
+	if !strings.Contains(buf.String(), `"File": "a.go"`) {
+		t.Fatalf("expected JSON to contain file name, got %s", buf.String())
+	}
+}
+
+func TestWriteSARIFIncludesEveryFinding(t *testing.T) {
+	var buf bytes.Buffer
+	findings := []Finding{
+		{File: "a.go", RuleID: ruleID, Severity: SeverityWarn, Message: "warn msg"},
+		{File: "b.go", RuleID: ruleID, Severity: SeverityFail, Message: "fail msg"},
+	}
+	if err := writeSARIF(&buf, findings); err != nil {
+		t.Fatalf("writeSARIF: %v", err)
+	}
+
+	out := buf.String()
+	for _, want := range []string{"a.go", "b.go", "warning", "error"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected SARIF output to contain %q, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestWriteCheckstyleGroupsErrorsByFileWithoutLosingEntries(t *testing.T) {
+	var buf bytes.Buffer
+	findings := []Finding{
+		{File: "a.go", RuleID: ruleID, Severity: SeverityWarn, Message: "a issue 1"},
+		{File: "b.go", RuleID: ruleID, Severity: SeverityWarn, Message: "b issue 1"},
+		{File: "a.go", RuleID: ruleID, Severity: SeverityFail, Message: "a issue 2"},
+	}
+	if err := writeCheckstyle(&buf, findings); err != nil {
+		t.Fatalf("writeCheckstyle: %v", err)
+	}
+
+	root := &checkstyleRoot{}
+	if err := xml.Unmarshal(buf.Bytes(), root); err != nil {
+		t.Fatalf("unmarshalling checkstyle output: %v", err)
+	}
+
+	if len(root.Files) != 2 {
+		t.Fatalf("expected 2 files, got %d: %+v", len(root.Files), root.Files)
+	}
+
+	for _, f := range root.Files {
+		if f.Name == "a.go" && len(f.Errors) != 2 {
+			t.Errorf("expected a.go to keep both its errors, got %d: %+v", len(f.Errors), f.Errors)
+		}
+		if f.Name == "b.go" && len(f.Errors) != 1 {
+			t.Errorf("expected b.go to have 1 error, got %d: %+v", len(f.Errors), f.Errors)
+		}
+	}
+}
+
+func TestWriteFindingsRejectsUnknownFormat(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeFindings(&buf, OutputText, nil); err == nil {
+		t.Fatal("expected writeFindings to reject a non-machine format")
+	}
+}
+
+var errFixture = fixtureError{}
+
+type fixtureError struct{}
+
+func (fixtureError) Error() string { return "fixture error" }
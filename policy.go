@@ -0,0 +1,162 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// defaultPolicyPath is where we look for the policy file relative to
+// the directory pre-commit invokes the hook from.
+const defaultPolicyPath = ".bestpractices.yaml"
+
+// Severity controls whether a failing rule only warns or blocks the
+// commit outright.
+type Severity string
+
+const (
+	SeverityWarn Severity = "warn"
+	SeverityFail Severity = "fail"
+)
+
+// Rule is a single best-practice check the LLM should enforce, e.g.
+// "naming" or "error-wrapping".
+type Rule struct {
+	Name     string   `yaml:"name"`
+	Severity Severity `yaml:"severity"`
+}
+
+// Policy is the contents of .bestpractices.yaml. It replaces the
+// hardcoded 8000-rune and 20-file limits and the fixed system prompt
+// with user-configurable rules and thresholds.
+type Policy struct {
+	Rules        []Rule   `yaml:"rules"`
+	Include      []string `yaml:"include"`
+	Exclude      []string `yaml:"exclude"`
+	MaxFiles     int      `yaml:"max_files"`
+	MaxChars     int      `yaml:"max_chars"`
+	IncludeTests bool     `yaml:"include_tests"`
+	RunLinters   bool     `yaml:"run_linters"`
+}
+
+// defaultPolicy preserves today's behavior when no .bestpractices.yaml
+// is present: every rule warns, nothing is excluded, same thresholds.
+func defaultPolicy() *Policy {
+	return &Policy{
+		Rules: []Rule{
+			{Name: "naming", Severity: SeverityWarn},
+			{Name: "error-wrapping", Severity: SeverityWarn},
+			{Name: "context-propagation", Severity: SeverityWarn},
+			{Name: "package-comments", Severity: SeverityWarn},
+		},
+		MaxFiles: 20,
+		MaxChars: 8000,
+	}
+}
+
+// loadPolicy reads and validates the policy file at path. If the file
+// does not exist, the default policy is returned unchanged.
+func loadPolicy(path string) (*Policy, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return defaultPolicy(), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading policy file %s: %v", path, err)
+	}
+
+	policy := defaultPolicy()
+	policy.Rules = nil
+	if err := yaml.Unmarshal(data, policy); err != nil {
+		return nil, fmt.Errorf("error parsing policy file %s: %v", path, err)
+	}
+
+	if err := policy.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid policy file %s: %v", path, err)
+	}
+
+	return policy, nil
+}
+
+// Validate rejects malformed policies at startup with a clear error
+// rather than failing confusingly later on.
+func (p *Policy) Validate() error {
+	if len(p.Rules) == 0 {
+		return fmt.Errorf("policy must declare at least one rule")
+	}
+
+	seen := make(map[string]bool, len(p.Rules))
+	for _, rule := range p.Rules {
+		if rule.Name == "" {
+			return fmt.Errorf("rule name cannot be empty")
+		}
+		if seen[rule.Name] {
+			return fmt.Errorf("duplicate rule %q", rule.Name)
+		}
+		seen[rule.Name] = true
+
+		switch rule.Severity {
+		case SeverityWarn, SeverityFail:
+		default:
+			return fmt.Errorf("rule %q has unknown severity %q", rule.Name, rule.Severity)
+		}
+	}
+
+	if p.MaxFiles <= 0 {
+		return fmt.Errorf("max_files must be positive, got %d", p.MaxFiles)
+	}
+	if p.MaxChars <= 0 {
+		return fmt.Errorf("max_chars must be positive, got %d", p.MaxChars)
+	}
+
+	return nil
+}
+
+// FailSeverity reports whether any rule in the policy is configured to
+// fail the commit rather than just warn. The LLM reports a single
+// pass/fail verdict per file rather than attributing it to one rule, so
+// this is necessarily an all-or-nothing switch today: if any rule is
+// "fail", any flagged file blocks the commit.
+func (p *Policy) FailSeverity() bool {
+	for _, rule := range p.Rules {
+		if rule.Severity == SeverityFail {
+			return true
+		}
+	}
+	return false
+}
+
+// RuleNames returns the rule names in declaration order, used to build
+// the system prompt sent to the LLM.
+func (p *Policy) RuleNames() []string {
+	names := make([]string, len(p.Rules))
+	for i, rule := range p.Rules {
+		names[i] = rule.Name
+	}
+	return names
+}
+
+// systemPromptFor builds the system prompt for the given policy,
+// listing the rules it should enforce explicitly instead of relying on
+// the model to infer them.
+func systemPromptFor(p *Policy) string {
+	rules := ""
+	for _, name := range p.RuleNames() {
+		rules += fmt.Sprintf("- %s\n", name)
+	}
+
+	return fmt.Sprintf(`You check go files given for best practices following the official style guide. You will reply in json format. Only reply with the json output and nothing more. The json response should have this format:
+				A "follows_best_practices" boolean fields and a "suggestions" string field.
+				Example:
+				{
+					"follows_best_practices": false,
+					"suggestions": "The function name ParseYAMLConfig does not follow the Go best practices as it's repeating the package name bla bla bla..."
+				}
+		    Do NOT include any other field in the json response.
+			Suggestions need to be as short and concise as possible, there can be no suggestions if the file appears to be following the best practices. But always indicate suggestions if the file does not follow the best practices.
+			You are only given files that have been modified in the current commit so you will lack some context, do not criticize the lack of context. Only check for the best practices that you can observe in the file you are checking at the moment.
+			Do not criticize whether the logic makes sense only check for go best practices. You will reply with a json response.
+			Only enforce the following rules, in order of importance:
+%s			You may be given a list of issues already detected by gofmt/go vet/golangci-lint under "Already-detected issues". Do not repeat those, focus on higher-level style and idiom feedback instead.`, rules)
+}
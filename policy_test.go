@@ -0,0 +1,108 @@
+package main
+
+import (
+	"os"
+	"testing"
+)
+
+func TestPolicyValidate(t *testing.T) {
+	validRules := []Rule{{Name: "naming", Severity: SeverityWarn}}
+
+	cases := []struct {
+		name    string
+		policy  *Policy
+		wantErr bool
+	}{
+		{
+			name:   "valid default policy",
+			policy: defaultPolicy(),
+		},
+		{
+			name:    "no rules",
+			policy:  &Policy{Rules: nil, MaxFiles: 20, MaxChars: 8000},
+			wantErr: true,
+		},
+		{
+			name:    "empty rule name",
+			policy:  &Policy{Rules: []Rule{{Name: "", Severity: SeverityWarn}}, MaxFiles: 20, MaxChars: 8000},
+			wantErr: true,
+		},
+		{
+			name: "duplicate rule name",
+			policy: &Policy{
+				Rules:    []Rule{{Name: "naming", Severity: SeverityWarn}, {Name: "naming", Severity: SeverityFail}},
+				MaxFiles: 20,
+				MaxChars: 8000,
+			},
+			wantErr: true,
+		},
+		{
+			name:    "unknown severity",
+			policy:  &Policy{Rules: []Rule{{Name: "naming", Severity: "critical"}}, MaxFiles: 20, MaxChars: 8000},
+			wantErr: true,
+		},
+		{
+			name:    "non-positive max_files",
+			policy:  &Policy{Rules: validRules, MaxFiles: 0, MaxChars: 8000},
+			wantErr: true,
+		},
+		{
+			name:    "negative max_chars",
+			policy:  &Policy{Rules: validRules, MaxFiles: 20, MaxChars: -1},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.policy.Validate()
+			if tc.wantErr && err == nil {
+				t.Fatalf("expected an error, got nil")
+			}
+			if !tc.wantErr && err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestLoadPolicyMissingFileReturnsDefault(t *testing.T) {
+	policy, err := loadPolicy("does-not-exist.yaml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(policy.Rules) != len(defaultPolicy().Rules) {
+		t.Fatalf("expected default policy, got %+v", policy)
+	}
+}
+
+func TestLoadPolicyRejectsInvalidPolicy(t *testing.T) {
+	path := t.TempDir() + "/bestpractices.yaml"
+	if err := os.WriteFile(path, []byte("rules: []\n"), 0o644); err != nil {
+		t.Fatalf("writing fixture policy: %v", err)
+	}
+
+	if _, err := loadPolicy(path); err == nil {
+		t.Fatal("expected an error for a policy with no rules")
+	}
+}
+
+func TestFailSeverity(t *testing.T) {
+	warnOnly := &Policy{Rules: []Rule{{Name: "naming", Severity: SeverityWarn}}}
+	if warnOnly.FailSeverity() {
+		t.Error("expected FailSeverity to be false when every rule only warns")
+	}
+
+	withFail := &Policy{Rules: []Rule{{Name: "naming", Severity: SeverityWarn}, {Name: "error-wrapping", Severity: SeverityFail}}}
+	if !withFail.FailSeverity() {
+		t.Error("expected FailSeverity to be true when any rule is set to fail")
+	}
+}
+
+func TestRuleNames(t *testing.T) {
+	policy := &Policy{Rules: []Rule{{Name: "naming"}, {Name: "error-wrapping"}}}
+	names := policy.RuleNames()
+	if len(names) != 2 || names[0] != "naming" || names[1] != "error-wrapping" {
+		t.Fatalf("expected rule names in declaration order, got %v", names)
+	}
+}
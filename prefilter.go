@@ -0,0 +1,67 @@
+package main
+
+import (
+	"bytes"
+	"go/parser"
+	"go/token"
+	"strings"
+)
+
+// generatedMarker is the convention documented by
+// https://go.dev/s/generatedcode: a line matching this exact pattern
+// anywhere in the file marks it as generated.
+const generatedMarker = "// Code generated"
+
+// isVendored reports whether file lives under a vendor/ directory.
+func isVendored(file string) bool {
+	return strings.Contains(file, "/vendor/") || strings.HasPrefix(file, "vendor/")
+}
+
+// isTestFile reports whether file is a _test.go file.
+func isTestFile(file string) bool {
+	return strings.HasSuffix(file, "_test.go")
+}
+
+// isGeneratedSource reports whether content carries the standard
+// "Code generated ... DO NOT EDIT." marker.
+func isGeneratedSource(content []byte) bool {
+	for _, line := range bytes.Split(content, []byte("\n")) {
+		trimmed := strings.TrimSpace(string(line))
+		if strings.HasPrefix(trimmed, generatedMarker) && strings.HasSuffix(trimmed, "DO NOT EDIT.") {
+			return true
+		}
+	}
+	return false
+}
+
+// isParseableGo reports whether content parses as a valid Go source
+// file. Files that fail this cheap check are skipped rather than sent
+// to the LLM, since they're either not really Go or mid-edit.
+func isParseableGo(file string, content []byte) bool {
+	fset := token.NewFileSet()
+	_, err := parser.ParseFile(fset, file, content, parser.PackageClauseOnly)
+	return err == nil
+}
+
+// preFilter decides whether file should be skipped before ever
+// touching the LLM, and if so why. policy.IncludeTests opts _test.go
+// files back in.
+func preFilter(policy *Policy, file string, content []byte) (skip bool, reason string) {
+	if isVendored(file) {
+		return true, "Skipping vendored file " + file
+	}
+
+	if isTestFile(file) && !policy.IncludeTests {
+		return true, "Skipping test file " + file
+	}
+
+	if isGeneratedSource(content) {
+		return true, "Skipping generated file " + file
+	}
+
+	if !isParseableGo(file, content) {
+		return true, "Skipping file " + file + " as it does not parse as valid Go"
+	}
+
+	return false, ""
+}
@@ -0,0 +1,79 @@
+package main
+
+import "testing"
+
+func TestIsVendored(t *testing.T) {
+	cases := map[string]bool{
+		"vendor/github.com/foo/bar.go": true,
+		"pkg/vendor/foo/bar.go":        true,
+		"pkg/vendored/foo.go":          false,
+		"cmd/main.go":                  false,
+	}
+	for file, want := range cases {
+		if got := isVendored(file); got != want {
+			t.Errorf("isVendored(%q) = %v, want %v", file, got, want)
+		}
+	}
+}
+
+func TestIsTestFile(t *testing.T) {
+	if !isTestFile("worker_test.go") {
+		t.Error("expected worker_test.go to be a test file")
+	}
+	if isTestFile("worker.go") {
+		t.Error("expected worker.go not to be a test file")
+	}
+}
+
+func TestIsGeneratedSource(t *testing.T) {
+	generated := []byte("// Code generated by protoc-gen-go. DO NOT EDIT.\npackage main\n")
+	if !isGeneratedSource(generated) {
+		t.Error("expected standard generated-code marker to be detected")
+	}
+
+	handWritten := []byte("// Package main does things.\npackage main\n")
+	if isGeneratedSource(handWritten) {
+		t.Error("expected hand-written source not to be flagged as generated")
+	}
+}
+
+func TestIsParseableGo(t *testing.T) {
+	valid := []byte("package main\n\nfunc main() {}\n")
+	if !isParseableGo("main.go", valid) {
+		t.Error("expected syntactically valid Go to parse")
+	}
+
+	// isParseableGo only parses far enough to read the package clause,
+	// so it's the clause itself that needs to be broken to trip it.
+	invalid := []byte("this is not even close to Go source\n")
+	if isParseableGo("main.go", invalid) {
+		t.Error("expected a file with no valid package clause not to parse")
+	}
+}
+
+func TestPreFilter(t *testing.T) {
+	policy := defaultPolicy()
+	validSrc := []byte("package main\n\nfunc main() {}\n")
+
+	if skip, reason := preFilter(policy, "vendor/foo.go", validSrc); !skip {
+		t.Errorf("expected vendored file to be skipped, reason=%q", reason)
+	}
+
+	if skip, _ := preFilter(policy, "foo_test.go", validSrc); !skip {
+		t.Error("expected _test.go to be skipped by default")
+	}
+
+	policy.IncludeTests = true
+	if skip, reason := preFilter(policy, "foo_test.go", validSrc); skip {
+		t.Errorf("expected _test.go to be allowed when IncludeTests is set, reason=%q", reason)
+	}
+
+	generated := []byte("// Code generated by stringer. DO NOT EDIT.\npackage main\n")
+	if skip, reason := preFilter(defaultPolicy(), "foo.go", generated); !skip {
+		t.Errorf("expected generated file to be skipped, reason=%q", reason)
+	}
+
+	if skip, reason := preFilter(defaultPolicy(), "foo.go", validSrc); skip {
+		t.Errorf("expected a regular file to pass the prefilter, reason=%q", reason)
+	}
+}
@@ -0,0 +1,421 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+)
+
+// ChatCompletionClient is implemented by each supported LLM backend. A
+// client is responsible for sending the system/user prompt to its
+// provider and returning a response that already matches the
+// LLMResponse schema, regardless of how that provider expresses JSON
+// mode or tool calling under the hood.
+type ChatCompletionClient interface {
+	Complete(ctx context.Context, system, prompt string) (*LLMResponse, error)
+}
+
+// Config holds the provider selection read from the environment.
+type Config struct {
+	Provider string // "ollama" (default), "openai", "anthropic" or "google"
+	Model    string
+	BaseURL  string
+	APIKey   string
+
+	CacheDir     string
+	CacheTTL     time.Duration
+	CacheMaxSize int64
+}
+
+const defaultModel = "qwen2.5-coder:7b"
+
+// loadConfig reads the BESTPRACTICES_* environment variables and fills
+// in provider-specific defaults for anything left unset.
+func loadConfig() *Config {
+	cfg := &Config{
+		Provider: os.Getenv("BESTPRACTICES_PROVIDER"),
+		Model:    os.Getenv("BESTPRACTICES_MODEL"),
+		BaseURL:  os.Getenv("BESTPRACTICES_BASE_URL"),
+		APIKey:   os.Getenv("BESTPRACTICES_API_KEY"),
+
+		CacheDir:     os.Getenv("BESTPRACTICES_CACHE_DIR"),
+		CacheTTL:     defaultCacheTTL,
+		CacheMaxSize: defaultCacheMaxSize,
+	}
+
+	if cfg.Provider == "" {
+		cfg.Provider = "ollama"
+	}
+
+	if cfg.Model == "" {
+		cfg.Model = defaultModel
+	}
+
+	if cfg.CacheDir == "" {
+		cfg.CacheDir = defaultCacheDir()
+	}
+
+	if ttl := os.Getenv("BESTPRACTICES_CACHE_TTL"); ttl != "" {
+		if d, err := time.ParseDuration(ttl); err == nil {
+			cfg.CacheTTL = d
+		}
+	}
+
+	if maxSize := os.Getenv("BESTPRACTICES_CACHE_MAX_SIZE"); maxSize != "" {
+		if n, err := strconv.ParseInt(maxSize, 10, 64); err == nil {
+			cfg.CacheMaxSize = n
+		}
+	}
+
+	return cfg
+}
+
+// newClient builds the ChatCompletionClient selected by cfg.Provider.
+func newClient(cfg *Config) (ChatCompletionClient, error) {
+	switch cfg.Provider {
+	case "ollama":
+		baseURL := cfg.BaseURL
+		if baseURL == "" {
+			baseURL = fmt.Sprintf("http://127.0.0.1:%d", ollamaPort)
+		}
+		return &ollamaClient{model: cfg.Model, baseURL: baseURL}, nil
+	case "openai":
+		baseURL := cfg.BaseURL
+		if baseURL == "" {
+			baseURL = "https://api.openai.com/v1"
+		}
+		return &openAIClient{model: cfg.Model, baseURL: baseURL, apiKey: cfg.APIKey}, nil
+	case "anthropic":
+		baseURL := cfg.BaseURL
+		if baseURL == "" {
+			baseURL = "https://api.anthropic.com/v1"
+		}
+		return &anthropicClient{model: cfg.Model, baseURL: baseURL, apiKey: cfg.APIKey}, nil
+	case "google":
+		baseURL := cfg.BaseURL
+		if baseURL == "" {
+			baseURL = "https://generativelanguage.googleapis.com/v1beta"
+		}
+		return &googleClient{model: cfg.Model, baseURL: baseURL, apiKey: cfg.APIKey}, nil
+	default:
+		return nil, fmt.Errorf("unknown BESTPRACTICES_PROVIDER %q", cfg.Provider)
+	}
+}
+
+// parseLLMJSON decodes the model's raw text reply into an LLMResponse,
+// shared by every backend since they all agree on the same JSON schema.
+func parseLLMJSON(raw string) (*LLMResponse, error) {
+	llmResponse := &LLMResponse{}
+	if err := json.Unmarshal([]byte(raw), llmResponse); err != nil {
+		return nil, fmt.Errorf("error unmarshalling LLM response: %v", err)
+	}
+	return llmResponse, nil
+}
+
+// ollamaClient talks to a local `ollama serve` instance. This is the
+// original behavior of queryLLM, unchanged.
+type ollamaClient struct {
+	model   string
+	baseURL string
+}
+
+type ollamaRequest struct {
+	Model  string `json:"model"`
+	Prompt string `json:"prompt"`
+	Format string `json:"format"`
+	System string `json:"system"`
+	Stream bool   `json:"stream"`
+}
+
+type ollamaResponse struct {
+	Response string `json:"response"`
+	Done     bool   `json:"done"`
+}
+
+func (c *ollamaClient) Complete(ctx context.Context, system, prompt string) (*LLMResponse, error) {
+	llmRequest := &ollamaRequest{
+		Model:  c.model,
+		System: system,
+		Prompt: prompt,
+		Format: "json",
+		Stream: true,
+	}
+
+	requestBody, err := json.Marshal(llmRequest)
+	if err != nil {
+		return nil, fmt.Errorf("error marshalling request: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/api/generate", bytes.NewBuffer(requestBody))
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %v", err)
+	}
+	req.Header.Add("Content-Type", "application/json")
+
+	client := &http.Client{}
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error running ollama command")
+	}
+	defer res.Body.Close()
+
+	raw, err := assembleStream(res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseLLMJSON(raw)
+}
+
+// assembleStream reads Ollama's newline-delimited JSON chunks, each
+// carrying a partial "response" fragment and a "done" boolean, and
+// concatenates them into the full response text.
+func assembleStream(r io.Reader) (string, error) {
+	var sb bytes.Buffer
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		chunk := &ollamaResponse{}
+		if err := json.Unmarshal(line, chunk); err != nil {
+			return "", fmt.Errorf("error unmarshalling response chunk: %v", err)
+		}
+
+		sb.WriteString(chunk.Response)
+		if chunk.Done {
+			break
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return "", fmt.Errorf("error reading response stream: %v", err)
+	}
+
+	return sb.String(), nil
+}
+
+// openAIClient talks to any OpenAI-compatible chat completions endpoint,
+// using JSON mode via response_format.
+type openAIClient struct {
+	model   string
+	baseURL string
+	apiKey  string
+}
+
+type openAIChatRequest struct {
+	Model          string              `json:"model"`
+	Messages       []openAIChatMessage `json:"messages"`
+	ResponseFormat openAIResponseFmt   `json:"response_format"`
+}
+
+type openAIChatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type openAIResponseFmt struct {
+	Type string `json:"type"`
+}
+
+type openAIChatResponse struct {
+	Choices []struct {
+		Message openAIChatMessage `json:"message"`
+	} `json:"choices"`
+}
+
+func (c *openAIClient) Complete(ctx context.Context, system, prompt string) (*LLMResponse, error) {
+	llmRequest := &openAIChatRequest{
+		Model: c.model,
+		Messages: []openAIChatMessage{
+			{Role: "system", Content: system},
+			{Role: "user", Content: prompt},
+		},
+		ResponseFormat: openAIResponseFmt{Type: "json_object"},
+	}
+
+	requestBody, err := json.Marshal(llmRequest)
+	if err != nil {
+		return nil, fmt.Errorf("error marshalling request: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/chat/completions", bytes.NewBuffer(requestBody))
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %v", err)
+	}
+	req.Header.Add("Content-Type", "application/json")
+	req.Header.Add("Authorization", "Bearer "+c.apiKey)
+
+	client := &http.Client{}
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error calling openai-compatible endpoint: %v", err)
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading response body %v", err)
+	}
+
+	oResp := &openAIChatResponse{}
+	if err := json.Unmarshal(body, oResp); err != nil {
+		return nil, fmt.Errorf("error unmarshalling response: %v", err)
+	}
+	if len(oResp.Choices) == 0 {
+		return nil, fmt.Errorf("openai-compatible endpoint returned no choices")
+	}
+
+	return parseLLMJSON(oResp.Choices[0].Message.Content)
+}
+
+// anthropicClient talks to the Anthropic Messages API, requesting JSON
+// back through the system prompt since Messages has no dedicated JSON
+// mode.
+type anthropicClient struct {
+	model   string
+	baseURL string
+	apiKey  string
+}
+
+type anthropicRequest struct {
+	Model     string              `json:"model"`
+	System    string              `json:"system"`
+	Messages  []openAIChatMessage `json:"messages"`
+	MaxTokens int                 `json:"max_tokens"`
+}
+
+type anthropicResponse struct {
+	Content []struct {
+		Text string `json:"text"`
+	} `json:"content"`
+}
+
+func (c *anthropicClient) Complete(ctx context.Context, system, prompt string) (*LLMResponse, error) {
+	llmRequest := &anthropicRequest{
+		Model:     c.model,
+		System:    system,
+		Messages:  []openAIChatMessage{{Role: "user", Content: prompt}},
+		MaxTokens: 1024,
+	}
+
+	requestBody, err := json.Marshal(llmRequest)
+	if err != nil {
+		return nil, fmt.Errorf("error marshalling request: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/messages", bytes.NewBuffer(requestBody))
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %v", err)
+	}
+	req.Header.Add("Content-Type", "application/json")
+	req.Header.Add("x-api-key", c.apiKey)
+	req.Header.Add("anthropic-version", "2023-06-01")
+
+	client := &http.Client{}
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error calling anthropic: %v", err)
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading response body %v", err)
+	}
+
+	aResp := &anthropicResponse{}
+	if err := json.Unmarshal(body, aResp); err != nil {
+		return nil, fmt.Errorf("error unmarshalling response: %v", err)
+	}
+	if len(aResp.Content) == 0 {
+		return nil, fmt.Errorf("anthropic returned no content")
+	}
+
+	return parseLLMJSON(aResp.Content[0].Text)
+}
+
+// googleClient talks to the Gemini generateContent API, using
+// responseMimeType to request JSON back.
+type googleClient struct {
+	model   string
+	baseURL string
+	apiKey  string
+}
+
+type googleRequest struct {
+	SystemInstruction googleContent       `json:"systemInstruction"`
+	Contents          []googleContent     `json:"contents"`
+	GenerationConfig  googleGenerationCfg `json:"generationConfig"`
+}
+
+type googleContent struct {
+	Parts []googlePart `json:"parts"`
+}
+
+type googlePart struct {
+	Text string `json:"text"`
+}
+
+type googleGenerationCfg struct {
+	ResponseMimeType string `json:"responseMimeType"`
+}
+
+type googleResponse struct {
+	Candidates []struct {
+		Content googleContent `json:"content"`
+	} `json:"candidates"`
+}
+
+func (c *googleClient) Complete(ctx context.Context, system, prompt string) (*LLMResponse, error) {
+	llmRequest := &googleRequest{
+		SystemInstruction: googleContent{Parts: []googlePart{{Text: system}}},
+		Contents:          []googleContent{{Parts: []googlePart{{Text: prompt}}}},
+		GenerationConfig:  googleGenerationCfg{ResponseMimeType: "application/json"},
+	}
+
+	requestBody, err := json.Marshal(llmRequest)
+	if err != nil {
+		return nil, fmt.Errorf("error marshalling request: %v", err)
+	}
+
+	url := fmt.Sprintf("%s/models/%s:generateContent?key=%s", c.baseURL, c.model, c.apiKey)
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(requestBody))
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %v", err)
+	}
+	req.Header.Add("Content-Type", "application/json")
+
+	client := &http.Client{}
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error calling google: %v", err)
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading response body %v", err)
+	}
+
+	gResp := &googleResponse{}
+	if err := json.Unmarshal(body, gResp); err != nil {
+		return nil, fmt.Errorf("error unmarshalling response: %v", err)
+	}
+	if len(gResp.Candidates) == 0 || len(gResp.Candidates[0].Content.Parts) == 0 {
+		return nil, fmt.Errorf("google returned no candidates")
+	}
+
+	return parseLLMJSON(gResp.Candidates[0].Content.Parts[0].Text)
+}
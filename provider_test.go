@@ -0,0 +1,90 @@
+package main
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestParseLLMJSON(t *testing.T) {
+	resp, err := parseLLMJSON(`{"follows_best_practices": false, "suggestions": "rename the function"}`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.FollowsBestPractices {
+		t.Error("expected FollowsBestPractices to be false")
+	}
+	if resp.Suggestions != "rename the function" {
+		t.Errorf("expected suggestions to be preserved, got %q", resp.Suggestions)
+	}
+
+	if _, err := parseLLMJSON("not json"); err == nil {
+		t.Error("expected an error for non-JSON input")
+	}
+}
+
+func TestNewClientDispatchesOnProvider(t *testing.T) {
+	cases := []struct {
+		provider string
+		wantType ChatCompletionClient
+		wantErr  bool
+	}{
+		{provider: "ollama", wantType: &ollamaClient{}},
+		{provider: "openai", wantType: &openAIClient{}},
+		{provider: "anthropic", wantType: &anthropicClient{}},
+		{provider: "google", wantType: &googleClient{}},
+		{provider: "unknown", wantErr: true},
+	}
+
+	for _, tc := range cases {
+		client, err := newClient(&Config{Provider: tc.provider, Model: "test-model"})
+		if tc.wantErr {
+			if err == nil {
+				t.Errorf("provider %q: expected an error, got none", tc.provider)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("provider %q: unexpected error: %v", tc.provider, err)
+		}
+		if reflect.TypeOf(client) != reflect.TypeOf(tc.wantType) {
+			t.Errorf("provider %q: expected client type %T, got %T", tc.provider, tc.wantType, client)
+		}
+	}
+}
+
+// TestGoogleRequestUsesCamelCaseFields guards against the Gemini
+// generateContent payload regressing back to snake_case, which the
+// real API silently ignores.
+func TestGoogleRequestUsesCamelCaseFields(t *testing.T) {
+	req := &googleRequest{
+		SystemInstruction: googleContent{Parts: []googlePart{{Text: "be terse"}}},
+		Contents:          []googleContent{{Parts: []googlePart{{Text: "hello"}}}},
+		GenerationConfig:  googleGenerationCfg{ResponseMimeType: "application/json"},
+	}
+
+	data, err := json.Marshal(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var generic map[string]json.RawMessage
+	if err := json.Unmarshal(data, &generic); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, ok := generic["systemInstruction"]; !ok {
+		t.Errorf("expected systemInstruction field, got %s", data)
+	}
+	if _, ok := generic["generationConfig"]; !ok {
+		t.Errorf("expected generationConfig field, got %s", data)
+	}
+
+	var cfg map[string]json.RawMessage
+	if err := json.Unmarshal(generic["generationConfig"], &cfg); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := cfg["responseMimeType"]; !ok {
+		t.Errorf("expected responseMimeType field, got %s", generic["generationConfig"])
+	}
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// defaultQueryTimeout bounds a single file's LLM query so one stuck
+// request can't hang the whole run.
+const defaultQueryTimeout = 2 * time.Minute
+
+// analysisResult is what a worker produces for one file.
+type analysisResult struct {
+	index    int
+	file     string
+	response *LLMResponse
+	skipped  string // non-empty reason if the file was skipped outright
+	err      error
+}
+
+// analyzeFiles pipelines queryLLM calls across a bounded worker pool and
+// returns the results in the same order as files, regardless of which
+// worker finished first. cache may be nil, in which case every file is
+// queried live.
+func analyzeFiles(ctx context.Context, client ChatCompletionClient, cache *Cache, model, system string, policy *Policy, files []string, concurrency int) []analysisResult {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	jobs := make(chan int)
+	out := make(chan analysisResult)
+
+	var wg sync.WaitGroup
+	wg.Add(concurrency)
+	for i := 0; i < concurrency; i++ {
+		go func() {
+			defer wg.Done()
+			for idx := range jobs {
+				out <- runQuery(ctx, client, cache, model, system, policy, idx, files[idx])
+			}
+		}()
+	}
+
+	go func() {
+		defer close(jobs)
+		for i := range files {
+			jobs <- i
+		}
+	}()
+
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+
+	// Collector: buffer out-of-order results and emit them in
+	// deterministic filename order as the next expected index
+	// becomes available.
+	results := make([]analysisResult, len(files))
+	pending := make(map[int]analysisResult)
+	next := 0
+	for res := range out {
+		pending[res.index] = res
+		for {
+			r, ok := pending[next]
+			if !ok {
+				break
+			}
+			results[next] = r
+			delete(pending, next)
+			next++
+		}
+	}
+
+	return results
+}
+
+// runQuery reads and queries a single file under its own timeout, so a
+// stuck request doesn't block the rest of the pool. A cache hit skips
+// the LLM call entirely.
+func runQuery(ctx context.Context, client ChatCompletionClient, cache *Cache, model, system string, policy *Policy, index int, file string) analysisResult {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return analysisResult{index: index, file: file, err: fmt.Errorf("error reading file %s: %v", file, err)}
+	}
+
+	if skip, reason := preFilter(policy, file, content); skip {
+		return analysisResult{index: index, file: file, skipped: reason}
+	}
+
+	if utf8.RuneCountInString(string(content)) > policy.MaxChars {
+		return analysisResult{index: index, file: file, skipped: fmt.Sprintf("Skipping file %s as it has more than %d characters", file, policy.MaxChars)}
+	}
+
+	key := cacheKey(model, system, string(content), policy.RunLinters)
+	if cache != nil {
+		if resp, ok := cache.Get(key); ok {
+			return analysisResult{index: index, file: file, response: resp}
+		}
+	}
+
+	fileCtx, cancel := context.WithTimeout(ctx, defaultQueryTimeout)
+	defer cancel()
+
+	var lintFindings []string
+	if policy.RunLinters {
+		lintFindings = staticFindings(fileCtx, file)
+	}
+
+	resp, err := queryLLM(fileCtx, client, system, file, string(content), lintFindings)
+	if err != nil {
+		return analysisResult{index: index, file: file, err: fmt.Errorf("error querying LLM for file %s: %v", file, err)}
+	}
+
+	if cache != nil {
+		if err := cache.Put(key, resp); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: error writing cache entry for %s: %v\n", file, err)
+		}
+	}
+
+	return analysisResult{index: index, file: file, response: resp}
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// reorderingClient answers each Complete call with the file's own
+// suggestion text, but deliberately delays files in reverse order of
+// how they're queried (the first call sleeps longest), so workers in
+// the pool finish in a different order than they were queued.
+type reorderingClient struct {
+	calls int
+}
+
+func (c *reorderingClient) Complete(ctx context.Context, system, prompt string) (*LLMResponse, error) {
+	c.calls++
+	time.Sleep(time.Duration(10-c.calls) * time.Millisecond)
+	return &LLMResponse{FollowsBestPractices: true, Suggestions: prompt}, nil
+}
+
+// TestAnalyzeFilesPreservesFileOrder guards analyzeFiles' collector:
+// even though workers race and finish in whatever order the fake
+// client's artificial delays produce, results must come back indexed
+// by the original file order, not completion order.
+func TestAnalyzeFilesPreservesFileOrder(t *testing.T) {
+	dir := t.TempDir()
+
+	var files []string
+	for i := 0; i < 5; i++ {
+		path := filepath.Join(dir, fmt.Sprintf("file%d.go", i))
+		content := fmt.Sprintf("package fixture\n\nfunc F%d() {}\n", i)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("writing fixture %s: %v", path, err)
+		}
+		files = append(files, path)
+	}
+
+	client := &reorderingClient{}
+	policy := defaultPolicy()
+	policy.MaxChars = 1000
+
+	results := analyzeFiles(context.Background(), client, nil, "test-model", "system prompt", policy, files, 3)
+
+	if len(results) != len(files) {
+		t.Fatalf("expected %d results, got %d", len(files), len(results))
+	}
+	for i, res := range results {
+		if res.file != files[i] {
+			t.Errorf("result %d: expected file %s, got %s", i, files[i], res.file)
+		}
+		if res.index != i {
+			t.Errorf("result %d: expected index %d, got %d", i, i, res.index)
+		}
+		if res.err != nil {
+			t.Errorf("result %d: unexpected error %v", i, res.err)
+		}
+	}
+}